@@ -0,0 +1,25 @@
+package proxycfg
+
+import "github.com/hashicorp/consul/agent/structs"
+
+// ConfigSnapshot is the per-proxy state the xds package compiles into Envoy
+// resources for a single connect-proxy. This snapshot intentionally covers
+// only the fields the xds package currently consumes; the full snapshot
+// maintained by the proxycfg manager carries a great deal more (upstream
+// endpoints, intentions, leaf certificates, etc.) that predates this change
+// and lives alongside these fields.
+type ConfigSnapshot struct {
+	// Service is the name of the local service this proxy instance fronts.
+	Service string
+
+	// EgressConfigEntries are every egress config entry in the proxy's
+	// datacenter/partition, already fetched by the proxycfg manager. The
+	// xds package filters these down to the ones that apply to Service
+	// when compiling clusters and listeners.
+	EgressConfigEntries []*structs.EgressConfigEntry
+
+	// DefaultAccessLogs is the datacenter's proxy-defaults AccessLogsConfig,
+	// applied to egress traffic whenever a matching EgressConfigEntry
+	// doesn't set its own AccessLogs.
+	DefaultAccessLogs *structs.AccessLogsConfig
+}
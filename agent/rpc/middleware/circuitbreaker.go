@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// breakerState is the Hystrix-style state machine of a single method's
+// circuit breaker.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreakerConfig configures the per-method circuit breaker used by
+// NewCircuitBreaker. A zero value disables breaking entirely.
+type CircuitBreakerConfig struct {
+	// Breakers maps a fully-qualified gRPC method to its breaker settings.
+	// Methods not present here are governed by Default.
+	Breakers map[string]BreakerSettings
+
+	// Default is applied to any method not listed in Breakers. A zero
+	// ErrorThreshold means the breaker never trips.
+	Default BreakerSettings
+}
+
+// BreakerSettings tunes a single method's circuit breaker.
+type BreakerSettings struct {
+	// RollingWindow is the duration over which the error rate is measured.
+	RollingWindow time.Duration
+	// MinRequests is the minimum number of requests in RollingWindow before
+	// ErrorThreshold is evaluated. Prevents tripping on low traffic.
+	MinRequests int
+	// ErrorThreshold is the fraction (0, 1] of failed requests in
+	// RollingWindow that opens the breaker.
+	ErrorThreshold float64
+	// CooldownPeriod is how long the breaker stays open before allowing a
+	// single half-open probe request through.
+	CooldownPeriod time.Duration
+	// SuccessThreshold is the number of consecutive successful probes
+	// required in the half-open state before the breaker closes again.
+	SuccessThreshold int
+}
+
+func (s BreakerSettings) enabled() bool {
+	return s.ErrorThreshold > 0
+}
+
+// methodBreaker tracks the rolling error count and state for one method.
+type methodBreaker struct {
+	settings BreakerSettings
+
+	mu               sync.Mutex
+	state            breakerState
+	windowStart      time.Time
+	requests         int
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight bool
+	consecutiveOK    int
+}
+
+func newMethodBreaker(settings BreakerSettings) *methodBreaker {
+	return &methodBreaker{settings: settings, state: breakerClosed, windowStart: time.Now()}
+}
+
+// allow reports whether a request may proceed, and if so returns a recordFn
+// that must be called with the result once the request completes.
+func (b *methodBreaker) allow() (ok bool, record func(success bool)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.settings.CooldownPeriod {
+			return false, nil
+		}
+		// Cool-down elapsed: admit a single half-open probe.
+		if b.halfOpenInFlight {
+			return false, nil
+		}
+		b.state = breakerHalfOpen
+		b.halfOpenInFlight = true
+		return true, b.recordHalfOpen
+	case breakerHalfOpen:
+		// Only one probe is allowed in flight at a time, but once that probe
+		// completes (successfully or not) another may be admitted: a success
+		// that doesn't yet meet SuccessThreshold must still let further
+		// probes through, or the breaker would wedge open forever.
+		if b.halfOpenInFlight {
+			return false, nil
+		}
+		b.halfOpenInFlight = true
+		return true, b.recordHalfOpen
+	default:
+		b.rollWindowLocked()
+		b.requests++
+		return true, b.recordClosed
+	}
+}
+
+func (b *methodBreaker) rollWindowLocked() {
+	if time.Since(b.windowStart) >= b.settings.RollingWindow {
+		b.windowStart = time.Now()
+		b.requests = 0
+		b.failures = 0
+	}
+}
+
+func (b *methodBreaker) recordClosed(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !success {
+		b.failures++
+	}
+	if b.requests >= b.settings.MinRequests && b.requests > 0 {
+		if float64(b.failures)/float64(b.requests) >= b.settings.ErrorThreshold {
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+func (b *methodBreaker) recordHalfOpen(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenInFlight = false
+	if !success {
+		b.consecutiveOK = 0
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.consecutiveOK++
+	if b.consecutiveOK >= b.settings.SuccessThreshold {
+		b.state = breakerClosed
+		b.consecutiveOK = 0
+		b.windowStart = time.Now()
+		b.requests = 0
+		b.failures = 0
+	}
+}
+
+// CircuitBreaker trips per-method gRPC traffic when its recent error rate
+// crosses a configured threshold, shedding load with codes.Unavailable until
+// a half-open probe succeeds N times in a row.
+type CircuitBreaker struct {
+	logger hclog.Logger
+
+	mu       sync.RWMutex
+	cfg      CircuitBreakerConfig
+	breakers map[string]*methodBreaker
+}
+
+// NewCircuitBreaker creates a CircuitBreaker from the given configuration.
+func NewCircuitBreaker(logger hclog.Logger, cfg CircuitBreakerConfig) *CircuitBreaker {
+	cb := &CircuitBreaker{logger: logger.Named("circuit_breaker")}
+	cb.UpdateConfig(cfg)
+	return cb
+}
+
+// UpdateConfig replaces the active configuration, resetting all per-method
+// breaker state so that a reload (see Interceptors.Reload) takes effect
+// immediately.
+func (cb *CircuitBreaker) UpdateConfig(cfg CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.cfg = cfg
+	cb.breakers = make(map[string]*methodBreaker, len(cfg.Breakers))
+}
+
+func (cb *CircuitBreaker) breakerForMethod(method string) *methodBreaker {
+	cb.mu.RLock()
+	if b, ok := cb.breakers[method]; ok {
+		cb.mu.RUnlock()
+		return b
+	}
+	settings, ok := cb.cfg.Breakers[method]
+	if !ok {
+		settings = cb.cfg.Default
+	}
+	cb.mu.RUnlock()
+
+	if !settings.enabled() {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if b, ok := cb.breakers[method]; ok {
+		return b
+	}
+	b := newMethodBreaker(settings)
+	cb.breakers[method] = b
+	return b
+}
+
+// UnaryInterceptor returns a grpc.UnaryServerInterceptor that rejects calls
+// with codes.Unavailable while the method's breaker is open.
+func (cb *CircuitBreaker) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		b := cb.breakerForMethod(info.FullMethod)
+		if b == nil {
+			return handler(ctx, req)
+		}
+
+		ok, record := b.allow()
+		if !ok {
+			cb.logger.Warn("rejecting request: circuit breaker open", "method", info.FullMethod)
+			return nil, status.Errorf(codes.Unavailable, "rpc: circuit breaker open for method %q", info.FullMethod)
+		}
+
+		resp, err := handler(ctx, req)
+		record(err == nil)
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns a grpc.StreamServerInterceptor counterpart of
+// UnaryInterceptor, checked once at stream open and recorded once the
+// stream handler returns.
+func (cb *CircuitBreaker) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		b := cb.breakerForMethod(info.FullMethod)
+		if b == nil {
+			return handler(srv, ss)
+		}
+
+		ok, record := b.allow()
+		if !ok {
+			cb.logger.Warn("rejecting stream: circuit breaker open", "method", info.FullMethod)
+			return status.Errorf(codes.Unavailable, "rpc: circuit breaker open for method %q", info.FullMethod)
+		}
+
+		err := handler(srv, ss)
+		record(err == nil)
+		return err
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestMethodBreaker_ClosedStaysClosedBelowMinRequests(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      10,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   time.Hour,
+		SuccessThreshold: 1,
+	})
+
+	for i := 0; i < 5; i++ {
+		ok, record := b.allow()
+		require.True(t, ok)
+		record(false)
+	}
+
+	ok, _ := b.allow()
+	require.True(t, ok, "breaker must stay closed until MinRequests is reached, regardless of error rate")
+}
+
+func TestMethodBreaker_OpensOnErrorThreshold(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      2,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   time.Hour,
+		SuccessThreshold: 1,
+	})
+
+	for i := 0; i < 2; i++ {
+		ok, record := b.allow()
+		require.True(t, ok)
+		record(false)
+	}
+
+	ok, _ := b.allow()
+	require.False(t, ok, "breaker must open once the error rate crosses ErrorThreshold")
+}
+
+func TestMethodBreaker_OpenRejectsUntilCooldownElapses(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      1,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   20 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+
+	ok, record := b.allow()
+	require.True(t, ok)
+	record(false)
+
+	ok, _ = b.allow()
+	require.False(t, ok, "breaker must reject while open and within the cooldown window")
+
+	time.Sleep(30 * time.Millisecond)
+
+	ok, probeRecord := b.allow()
+	require.True(t, ok, "breaker must admit a single probe once the cooldown elapses")
+	probeRecord(true)
+}
+
+func TestMethodBreaker_HalfOpenAdmitsOnlyOneProbeAtATime(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      1,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   20 * time.Millisecond,
+		SuccessThreshold: 2,
+	})
+
+	ok, record := b.allow()
+	require.True(t, ok)
+	record(false)
+	time.Sleep(30 * time.Millisecond)
+
+	ok, probeRecord := b.allow()
+	require.True(t, ok, "first probe must be admitted")
+
+	ok, _ = b.allow()
+	require.False(t, ok, "a second concurrent probe must be rejected while the first is in flight")
+
+	probeRecord(true)
+}
+
+// TestMethodBreaker_HalfOpenUnwedgesUntilSuccessThreshold is a regression
+// test: a probe that succeeds but doesn't yet satisfy SuccessThreshold must
+// still let another probe through on the next call, rather than rejecting
+// every request forever because the breaker never leaves the half-open
+// state.
+func TestMethodBreaker_HalfOpenUnwedgesUntilSuccessThreshold(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      1,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   20 * time.Millisecond,
+		SuccessThreshold: 2,
+	})
+
+	ok, record := b.allow()
+	require.True(t, ok)
+	record(false)
+	time.Sleep(30 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		ok, probeRecord := b.allow()
+		require.True(t, ok, "probe %d must be admitted", i)
+		probeRecord(true)
+	}
+
+	ok, _ = b.allow()
+	require.True(t, ok, "breaker must close after SuccessThreshold consecutive probe successes")
+}
+
+func TestMethodBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b := newMethodBreaker(BreakerSettings{
+		RollingWindow:    time.Minute,
+		MinRequests:      1,
+		ErrorThreshold:   0.5,
+		CooldownPeriod:   20 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+
+	ok, record := b.allow()
+	require.True(t, ok)
+	record(false)
+	time.Sleep(30 * time.Millisecond)
+
+	ok, probeRecord := b.allow()
+	require.True(t, ok)
+	probeRecord(false)
+
+	ok, _ = b.allow()
+	require.False(t, ok, "a failed probe must reopen the breaker")
+}
+
+func TestCircuitBreaker_DisabledByDefaultAlwaysAllows(t *testing.T) {
+	cb := NewCircuitBreaker(testLogger(), CircuitBreakerConfig{})
+	require.Nil(t, cb.breakerForMethod("/consul.Test/Method"), "a zero ErrorThreshold must disable breaking entirely")
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	interceptor := cb.UnaryInterceptor()
+
+	for i := 0; i < 10; i++ {
+		resp, err := interceptor(context.Background(), nil, info, handler)
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp)
+	}
+}
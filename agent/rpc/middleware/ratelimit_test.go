@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testLogger() hclog.Logger {
+	return hclog.NewNullLogger()
+}
+
+func echoHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+func TestRateLimiter_AllowsBurstThenRejects(t *testing.T) {
+	rl := NewRateLimiter(testLogger(), RateLimiterConfig{
+		Default: RateLimit{Rate: rate.Limit(0), Burst: 2}, // no refill within the test
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Method"}
+	interceptor := rl.UnaryInterceptor()
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(context.Background(), nil, info, echoHandler)
+		require.NoError(t, err, "request %d must be admitted within the configured burst", i)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, echoHandler)
+	require.Error(t, err, "the request past the burst must be rejected")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimiter_PerMethodLimitsAreIndependent(t *testing.T) {
+	rl := NewRateLimiter(testLogger(), RateLimiterConfig{
+		Limits: map[string]RateLimit{
+			"/consul.Test/Limited": {Rate: rate.Limit(0), Burst: 1},
+		},
+		Default: RateLimit{}, // unlimited
+	})
+
+	limited := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Limited"}
+	unlimited := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Unlimited"}
+	interceptor := rl.UnaryInterceptor()
+
+	_, err := interceptor(context.Background(), nil, limited, echoHandler)
+	require.NoError(t, err)
+
+	_, err = interceptor(context.Background(), nil, limited, echoHandler)
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	for i := 0; i < 5; i++ {
+		_, err := interceptor(context.Background(), nil, unlimited, echoHandler)
+		require.NoError(t, err, "a method with no configured limit must never be rejected")
+	}
+}
+
+func TestRateLimiter_ZeroValueConfigDisablesLimiting(t *testing.T) {
+	rl := NewRateLimiter(testLogger(), RateLimiterConfig{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Method"}
+	interceptor := rl.UnaryInterceptor()
+
+	for i := 0; i < 10; i++ {
+		_, err := interceptor(context.Background(), nil, info, echoHandler)
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimiter_UpdateConfigResetsLimiters(t *testing.T) {
+	rl := NewRateLimiter(testLogger(), RateLimiterConfig{
+		Default: RateLimit{Rate: rate.Limit(0), Burst: 1},
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/consul.Test/Method"}
+	interceptor := rl.UnaryInterceptor()
+
+	_, err := interceptor(context.Background(), nil, info, echoHandler)
+	require.NoError(t, err)
+	_, err = interceptor(context.Background(), nil, info, echoHandler)
+	require.Error(t, err, "the single burst token must already be spent")
+
+	rl.UpdateConfig(RateLimiterConfig{
+		Default: RateLimit{Rate: rate.Limit(0), Burst: 1},
+	})
+
+	_, err = interceptor(context.Background(), nil, info, echoHandler)
+	require.NoError(t, err, "a reloaded config must start each method's bucket fresh")
+}
@@ -1,25 +1,109 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-metrics"
+	"github.com/hashicorp/go-uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// NewPanicHandler returns a RecoveryHandlerFunc type function
-// to handle panic in RPC server's handlers.
-func NewPanicHandler(logger hclog.InterceptLogger) RecoveryHandlerFunc {
-	return func(p interface{}) (err error) {
-		// Log the panic and the stack trace of the Goroutine that caused the panic.
-		stacktrace := hclog.Stacktrace()
+// panicCounter is the metric emitted each time a gRPC handler panics,
+// labeled with the method that panicked: consul.rpc.server.panic{method=...}.
+var panicCounter = []string{"rpc", "server", "panic"}
+
+// PanicHandlerOption configures NewPanicHandler.
+type PanicHandlerOption func(*panicHandlerConfig)
+
+type panicHandlerConfig struct {
+	sink      metrics.MetricSink
+	panicHook func(p interface{}, method string)
+}
+
+// WithMetricsSink overrides the go-metrics sink the panic counter is emitted
+// to. If unset, the process-wide global sink is used.
+func WithMetricsSink(sink metrics.MetricSink) PanicHandlerOption {
+	return func(cfg *panicHandlerConfig) {
+		cfg.sink = sink
+	}
+}
+
+// WithPanicHook registers a callback invoked with the recovered panic value
+// and the gRPC method it occurred on, once the panic has been logged and
+// counted. Operators can use this to wire up alerting or tracing hooks.
+func WithPanicHook(hook func(p interface{}, method string)) PanicHandlerOption {
+	return func(cfg *panicHandlerConfig) {
+		cfg.panicHook = hook
+	}
+}
+
+// NewPanicHandler returns a RecoveryHandlerFunc to handle panics in the RPC
+// server's handlers. It logs the panic with the stack trace as a separate
+// structured field, increments a consul.rpc.server.panic{method=...}
+// counter, runs any configured panic hook, and returns a codes.Internal
+// status carrying a redacted summary plus a request-id detail rather than
+// leaking the original panic value to the client.
+func NewPanicHandler(logger hclog.InterceptLogger, opts ...PanicHandlerOption) RecoveryHandlerFunc {
+	cfg := &panicHandlerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, p interface{}) error {
+		method, _ := grpc.Method(ctx)
+
+		// Generate the correlation ID before logging so the log line can
+		// carry the same ID handed back to the client, letting operators grep
+		// server logs for the request id surfaced in a client-visible error.
+		reqID, err := uuid.GenerateUUID()
+		if err != nil {
+			reqID = "unknown"
+		}
+
+		// Log the panic and the stack trace of the Goroutine that caused the
+		// panic. The stack is passed as its own field rather than folded into
+		// the message so log ingestion can index it.
 		logger.Error("panic serving grpc request",
+			"method", method,
+			"request_id", reqID,
 			"panic", p,
-			"stack", stacktrace,
+			"stack", hclog.Stacktrace(),
 		)
 
-		// TODO: verify this is mapped to a proper error code/status in rpc?
-		return fmt.Errorf("rpc: panic serving request")
+		labels := []metrics.Label{{Name: "method", Value: method}}
+		if cfg.sink != nil {
+			cfg.sink.IncrCounterWithLabels(panicCounter, 1, labels)
+		} else {
+			metrics.IncrCounterWithLabels(panicCounter, 1, labels)
+		}
+
+		if cfg.panicHook != nil {
+			cfg.panicHook(p, method)
+		}
+
+		return panicStatusErr(method, reqID)
+	}
+}
+
+// panicStatusErr builds the codes.Internal status returned to the caller.
+// The original panic value is never included in the response; it is only
+// ever logged server-side. reqID is attached as an errdetails.RequestInfo so
+// operators can correlate the client-visible error with the corresponding
+// log line.
+func panicStatusErr(method, reqID string) error {
+	st, detailErr := status.New(codes.Internal, "rpc: panic serving request").
+		WithDetails(&errdetails.RequestInfo{RequestId: reqID})
+	if detailErr != nil {
+		return status.Errorf(codes.Internal, "rpc: panic serving request %q", method)
 	}
+	return st.Err()
 }
 
-type RecoveryHandlerFunc func(p interface{}) (err error)
+// RecoveryHandlerFunc mirrors go-grpc-middleware's context-aware recovery
+// handler signature, giving handlers access to the request context (and, via
+// grpc.Method, the method that panicked).
+type RecoveryHandlerFunc func(ctx context.Context, p interface{}) (err error)
@@ -0,0 +1,40 @@
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/acl"
+)
+
+// ConfigEntry is the interface implemented by every config entry kind. It's
+// the contract DecodeConfigEntry and MakeConfigEntry rely on to validate,
+// normalize, and authorize a config entry without knowing its concrete type.
+type ConfigEntry interface {
+	GetKind() string
+	GetName() string
+	GetMeta() map[string]string
+	GetEnterpriseMeta() *acl.EnterpriseMeta
+	GetRaftIndex() *RaftIndex
+
+	CanRead(authz acl.Authorizer) error
+	CanWrite(authz acl.Authorizer) error
+
+	Normalize() error
+	Validate() error
+}
+
+// MakeConfigEntry returns a zero-value ConfigEntry for kind, ready to be
+// decoded into and then Normalize/Validate'd. This is the switch that gates
+// which Kind strings DecodeConfigEntry, and in turn ConfigEntries().Set(),
+// will accept; a kind not listed here is rejected before it ever reaches its
+// type's own Validate.
+func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
+	switch kind {
+	case Egress:
+		return &EgressConfigEntry{Name: name}, nil
+	default:
+		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
+	}
+}
+
+var _ ConfigEntry = (*EgressConfigEntry)(nil)
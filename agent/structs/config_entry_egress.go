@@ -0,0 +1,152 @@
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/acl"
+)
+
+// Egress is the Kind value for an EgressConfigEntry.
+const Egress string = "egress"
+
+const (
+	// EgressProtocolTCP routes the destination by SNI, passing the TLS
+	// session through to the external host untouched.
+	EgressProtocolTCP = "tcp"
+	// EgressProtocolHTTP terminates the connection and proxies it onward
+	// via an HTTP CONNECT listener.
+	EgressProtocolHTTP = "http"
+)
+
+// EgressConfigEntry manages the configuration for an egress config entry.
+// It declares external hostnames and ports reachable through a connect
+// sidecar and compiles into Envoy clusters and listeners: SNI-based routing
+// for TLS passthrough on EgressProtocolTCP destinations, and an HTTP CONNECT
+// listener for EgressProtocolHTTP ones.
+type EgressConfigEntry struct {
+	Kind string
+	Name string
+
+	// Services restricts these rules to the named local services' sidecars.
+	// Empty means every service's sidecar in the partition.
+	Services []string
+
+	Destinations []EgressDestination
+
+	// AccessLogs overrides proxy-defaults' AccessLogsConfig for egress
+	// traffic covered by this entry, following the same shape used for
+	// inbound/outbound sidecar traffic.
+	AccessLogs *AccessLogsConfig
+
+	Meta map[string]string `json:",omitempty"`
+
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+// EgressDestination is a single external host, port, and protocol reachable
+// through a sidecar's egress listener.
+type EgressDestination struct {
+	Host     string
+	Port     int
+	Protocol string
+
+	// LocalBindAddress is the address the sidecar's egress listener for this
+	// destination binds to. Defaults to "127.0.0.1", the same default used
+	// for upstream local bind addresses.
+	LocalBindAddress string
+
+	// LocalBindPort is the port the sidecar's egress listener for this
+	// destination binds to, following the same LocalBindPort convention
+	// used for upstreams.
+	LocalBindPort int
+}
+
+func (e *EgressConfigEntry) GetKind() string {
+	return Egress
+}
+
+func (e *EgressConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+	return e.Name
+}
+
+func (e *EgressConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *EgressConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+	return &e.EnterpriseMeta
+}
+
+func (e *EgressConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+	return &e.RaftIndex
+}
+
+func (e *EgressConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.Kind = Egress
+	for i := range e.Destinations {
+		if e.Destinations[i].Protocol == "" {
+			e.Destinations[i].Protocol = EgressProtocolTCP
+		}
+		if e.Destinations[i].LocalBindAddress == "" {
+			e.Destinations[i].LocalBindAddress = "127.0.0.1"
+		}
+	}
+
+	e.EnterpriseMeta.Normalize()
+	return nil
+}
+
+func (e *EgressConfigEntry) Validate() error {
+	if len(e.Destinations) == 0 {
+		return fmt.Errorf("egress config entry must declare at least one destination")
+	}
+
+	for _, d := range e.Destinations {
+		if d.Host == "" {
+			return fmt.Errorf("egress destination must set a host")
+		}
+		if d.Port <= 0 || d.Port > 65535 {
+			return fmt.Errorf("egress destination %q: port must be between 1 and 65535, got %d", d.Host, d.Port)
+		}
+		if d.LocalBindPort <= 0 || d.LocalBindPort > 65535 {
+			return fmt.Errorf("egress destination %q: local_bind_port must be between 1 and 65535, got %d", d.Host, d.LocalBindPort)
+		}
+		switch d.Protocol {
+		case EgressProtocolTCP, EgressProtocolHTTP:
+		default:
+			return fmt.Errorf("egress destination %q: protocol must be %q or %q, got %q",
+				d.Host, EgressProtocolTCP, EgressProtocolHTTP, d.Protocol)
+		}
+	}
+
+	return nil
+}
+
+func (e *EgressConfigEntry) CanRead(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().ServiceReadAllowed(e.Name, &authzContext)
+}
+
+func (e *EgressConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().ServiceWriteAllowed(e.Name, &authzContext)
+}
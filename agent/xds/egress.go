@@ -0,0 +1,245 @@
+package xds
+
+import (
+	"fmt"
+
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_tls_inspector_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
+	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// egressClusterNamePrefix namespaces egress clusters so they can't collide
+// with upstream or local-service clusters generated elsewhere in this
+// package.
+const egressClusterNamePrefix = "egress~"
+
+// egressListenerNamePrefix namespaces egress listeners the same way.
+const egressListenerNamePrefix = "egress_listener~"
+
+// ResourcesForService returns the Envoy clusters and listeners compiled from
+// every egress config entry that applies to serviceName — either because the
+// entry names it explicitly in Services, or because the entry declares no
+// Services and therefore applies to every service's sidecar. It is called
+// from ResourceGenerator.resourcesFromSnapshotConnectProxy in resources.go,
+// the per-service aggregation point the xDS server pulls clusters and
+// listeners from, so an EgressConfigEntry actually contributes Envoy config
+// rather than going unused.
+func ResourcesForService(entries []*structs.EgressConfigEntry, serviceName string, defaultAccessLogs *structs.AccessLogsConfig) ([]*envoy_cluster_v3.Cluster, []*envoy_listener_v3.Listener, error) {
+	var clusters []*envoy_cluster_v3.Cluster
+	var listeners []*envoy_listener_v3.Listener
+
+	for _, entry := range entries {
+		if !egressEntryAppliesToService(entry, serviceName) {
+			continue
+		}
+
+		entryClusters, err := makeEgressClusters(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("egress config entry %q: %w", entry.Name, err)
+		}
+		entryListeners, err := makeEgressListeners(entry, defaultAccessLogs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("egress config entry %q: %w", entry.Name, err)
+		}
+
+		clusters = append(clusters, entryClusters...)
+		listeners = append(listeners, entryListeners...)
+	}
+
+	return clusters, listeners, nil
+}
+
+// egressEntryAppliesToService reports whether entry's rules cover
+// serviceName, per the Services field's documented semantics: empty means
+// global, otherwise serviceName must be listed explicitly.
+func egressEntryAppliesToService(entry *structs.EgressConfigEntry, serviceName string) bool {
+	if len(entry.Services) == 0 {
+		return true
+	}
+	for _, s := range entry.Services {
+		if s == serviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// makeEgressClusters builds one Envoy cluster per destination declared on
+// the entry, using STRICT_DNS discovery since the destinations are external
+// hostnames rather than service-mesh endpoints resolved via EDS.
+func makeEgressClusters(entry *structs.EgressConfigEntry) ([]*envoy_cluster_v3.Cluster, error) {
+	var clusters []*envoy_cluster_v3.Cluster
+
+	for _, dest := range entry.Destinations {
+		name := egressClusterName(dest)
+
+		cluster := &envoy_cluster_v3.Cluster{
+			Name:           name,
+			ConnectTimeout: durationpb.New(5_000_000_000), // 5s, matches the mesh default dial timeout
+			ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{
+				Type: envoy_cluster_v3.Cluster_STRICT_DNS,
+			},
+			LoadAssignment: &envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: name,
+				Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+					{
+						LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+							{
+								HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+									Endpoint: &envoy_endpoint_v3.Endpoint{
+										Address: &envoy_core_v3.Address{
+											Address: &envoy_core_v3.Address_SocketAddress{
+												SocketAddress: &envoy_core_v3.SocketAddress{
+													Address: dest.Host,
+													PortSpecifier: &envoy_core_v3.SocketAddress_PortValue{
+														PortValue: uint32(dest.Port),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+// makeEgressListeners builds one Envoy listener per destination: SNI-based
+// routing via the TCP proxy filter for EgressProtocolTCP (passthrough,
+// letting the client's own TLS handshake reach the external host), or an
+// HTTP CONNECT-capable listener for EgressProtocolHTTP.
+func makeEgressListeners(entry *structs.EgressConfigEntry, accessLog *structs.AccessLogsConfig) ([]*envoy_listener_v3.Listener, error) {
+	logCfg := entry.AccessLogs
+	if logCfg == nil {
+		logCfg = accessLog
+	}
+
+	var listeners []*envoy_listener_v3.Listener
+	for _, dest := range entry.Destinations {
+		switch dest.Protocol {
+		case structs.EgressProtocolTCP:
+			l, err := makeEgressSNIListener(dest, logCfg)
+			if err != nil {
+				return nil, err
+			}
+			listeners = append(listeners, l)
+		case structs.EgressProtocolHTTP:
+			l, err := makeEgressHTTPConnectListener(dest, logCfg)
+			if err != nil {
+				return nil, err
+			}
+			listeners = append(listeners, l)
+		default:
+			return nil, fmt.Errorf("egress destination %q: unsupported protocol %q", dest.Host, dest.Protocol)
+		}
+	}
+
+	return listeners, nil
+}
+
+func makeEgressSNIListener(dest structs.EgressDestination, accessLog *structs.AccessLogsConfig) (*envoy_listener_v3.Listener, error) {
+	tcpProxy := &envoy_tcp_proxy_v3.TcpProxy{
+		StatPrefix:       egressClusterName(dest),
+		ClusterSpecifier: &envoy_tcp_proxy_v3.TcpProxy_Cluster{Cluster: egressClusterName(dest)},
+	}
+	if accessLog != nil {
+		al, err := accessLogsFromConfig(accessLog)
+		if err != nil {
+			return nil, err
+		}
+		tcpProxy.AccessLog = al
+	}
+
+	filter, err := makeFilter("envoy.filters.network.tcp_proxy", tcpProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The TLS inspector reads the SNI from the ClientHello so that the
+	// ServerNames match below has a server name to compare against.
+	tlsInspector, err := anypb.New(&envoy_tls_inspector_v3.TlsInspector{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_listener_v3.Listener{
+		Name:    egressListenerName(dest),
+		Address: egressListenerAddress(dest),
+		ListenerFilters: []*envoy_listener_v3.ListenerFilter{
+			{
+				Name:       "envoy.filters.listener.tls_inspector",
+				ConfigType: &envoy_listener_v3.ListenerFilter_TypedConfig{TypedConfig: tlsInspector},
+			},
+		},
+		FilterChains: []*envoy_listener_v3.FilterChain{
+			{
+				// SNI-based routing: the filter chain match below selects this
+				// chain by the destination's hostname, and the TCP proxy filter
+				// forwards the still-encrypted bytes to the upstream cluster
+				// untouched (TLS passthrough).
+				FilterChainMatch: &envoy_listener_v3.FilterChainMatch{
+					ServerNames: []string{dest.Host},
+				},
+				Filters: []*envoy_listener_v3.Filter{filter},
+			},
+		},
+	}, nil
+}
+
+func makeEgressHTTPConnectListener(dest structs.EgressDestination, accessLog *structs.AccessLogsConfig) (*envoy_listener_v3.Listener, error) {
+	hcm, err := makeHTTPConnectionManager(egressListenerName(dest), egressClusterName(dest), accessLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_listener_v3.Listener{
+		Name:    egressListenerName(dest),
+		Address: egressListenerAddress(dest),
+		FilterChains: []*envoy_listener_v3.FilterChain{
+			{
+				Filters: []*envoy_listener_v3.Filter{hcm},
+			},
+		},
+	}, nil
+}
+
+func egressClusterName(dest structs.EgressDestination) string {
+	return fmt.Sprintf("%s%s:%d", egressClusterNamePrefix, dest.Host, dest.Port)
+}
+
+func egressListenerName(dest structs.EgressDestination) string {
+	return fmt.Sprintf("%s%s:%d", egressListenerNamePrefix, dest.Host, dest.Port)
+}
+
+// egressListenerAddress binds the egress listener to the destination's
+// configured local bind address/port, the same LocalBindAddress/
+// LocalBindPort convention used for upstream listeners elsewhere in this
+// package, so the compiled listener is actually reachable rather than left
+// without a bound socket.
+func egressListenerAddress(dest structs.EgressDestination) *envoy_core_v3.Address {
+	return &envoy_core_v3.Address{
+		Address: &envoy_core_v3.Address_SocketAddress{
+			SocketAddress: &envoy_core_v3.SocketAddress{
+				Address: dest.LocalBindAddress,
+				PortSpecifier: &envoy_core_v3.SocketAddress_PortValue{
+					PortValue: uint32(dest.LocalBindPort),
+				},
+			},
+		},
+	}
+}
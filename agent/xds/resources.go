@@ -0,0 +1,32 @@
+package xds
+
+import (
+	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+)
+
+// ResourceGenerator compiles a connect-proxy's ConfigSnapshot into the Envoy
+// resources the xDS server sends it. It is the per-service aggregation
+// point: each resource family (upstreams, egress, etc.) contributes its
+// clusters and listeners here rather than being wired up independently.
+type ResourceGenerator struct{}
+
+// resourcesFromSnapshotConnectProxy returns the clusters and listeners for a
+// single connect-proxy's snapshot. Today that's just the egress resources;
+// as other resource families in this package grow snapshot-driven generation
+// they append to the same slices here.
+func (g *ResourceGenerator) resourcesFromSnapshotConnectProxy(cfgSnap *proxycfg.ConfigSnapshot) ([]*envoy_cluster_v3.Cluster, []*envoy_listener_v3.Listener, error) {
+	var clusters []*envoy_cluster_v3.Cluster
+	var listeners []*envoy_listener_v3.Listener
+
+	egressClusters, egressListeners, err := ResourcesForService(cfgSnap.EgressConfigEntries, cfgSnap.Service, cfgSnap.DefaultAccessLogs)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusters = append(clusters, egressClusters...)
+	listeners = append(listeners, egressListeners...)
+
+	return clusters, listeners, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+// Egress is the Kind value for an EgressConfigEntry.
+const Egress string = "egress"
+
+// EgressConfigEntry manages the configuration for an egress config entry.
+// It declares external hostnames and ports reachable through a connect
+// sidecar, analogous to an Istio ServiceEntry + Sidecar egress rule: the
+// entry compiles into Envoy clusters and listeners with SNI-based routing
+// for TLS passthrough and HTTP CONNECT for plain HTTP traffic.
+type EgressConfigEntry struct {
+	// Kind of the config entry. This should be set to api.Egress.
+	Kind string
+
+	// Name is this config entry's identifier, used the same way Name is
+	// used on every other config entry kind (e.g. to address it for reads,
+	// updates, and deletes). It does not affect which services the entry
+	// applies to — see Services for that.
+	Name string
+
+	// Services restricts these rules to the named local services' sidecars.
+	// If empty, the rules apply globally to every service's sidecar.
+	Services []string
+
+	// Destinations are the external hosts and ports reachable through the
+	// sidecar.
+	Destinations []EgressDestination
+
+	// AccessLogs configures Envoy access logging for traffic proxied to
+	// these destinations. If unset, the proxy-defaults AccessLogsConfig (if
+	// any) is honored instead, the same as inbound/outbound sidecar traffic.
+	AccessLogs *AccessLogsConfig
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex and ModifyIndex are set by Consul and should not be
+	// modified by the client.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EgressDestination is a single external host, port, and protocol reachable
+// through a sidecar's egress listener.
+type EgressDestination struct {
+	// Host is the external DNS name or IP the sidecar is allowed to dial.
+	Host string
+
+	// Port is the destination port, required and between 1 and 65535.
+	Port int
+
+	// Protocol selects how the sidecar proxies the connection: "tcp" uses
+	// SNI-based routing to pass TLS through untouched, "http" terminates
+	// the connection and proxies it via an HTTP CONNECT listener. Defaults
+	// to "tcp".
+	Protocol string
+
+	// LocalBindAddress is the address the sidecar's egress listener for this
+	// destination binds to. Defaults to "127.0.0.1".
+	LocalBindAddress string
+
+	// LocalBindPort is the port the sidecar's egress listener for this
+	// destination binds to, required and between 1 and 65535.
+	LocalBindPort int
+}
+
+func (e *EgressConfigEntry) GetKind() string {
+	return Egress
+}
+
+func (e *EgressConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+	return e.Name
+}
+
+func (e *EgressConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *EgressConfigEntry) GetCreateIndex() uint64 {
+	if e == nil {
+		return 0
+	}
+	return e.CreateIndex
+}
+
+func (e *EgressConfigEntry) GetModifyIndex() uint64 {
+	if e == nil {
+		return 0
+	}
+	return e.ModifyIndex
+}
@@ -0,0 +1,137 @@
+package observability
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/semver"
+
+	"github.com/hashicorp/consul/api"
+	libassert "github.com/hashicorp/consul/test/integration/consul-container/libs/assert"
+	libservice "github.com/hashicorp/consul/test/integration/consul-container/libs/service"
+	"github.com/hashicorp/consul/test/integration/consul-container/libs/utils"
+)
+
+// egressLocalBindPort is the port the egress listener below is configured to
+// bind to inside the sidecar container. It's unused by any other service in
+// this test file's cluster, so there's no risk of colliding with an upstream
+// listener's local bind port.
+const egressLocalBindPort = 21100
+
+// TestEgressConfigEntry Summary
+// This test mirrors TestAccessLogs in access_logs_test.go: it ensures that an `egress` config
+// entry compiles into a real, bound Envoy listener for external-service
+// traffic through the connect sidecar, and that the traffic is access-logged
+// the same way inbound/outbound sidecar traffic is.
+//
+// Steps:
+//   - Create a single agent cluster.
+//   - Enable default access logs, same as TestAccessLogs, so the egress
+//     listener inherits the configuration.
+//   - Create a static-client sidecar and register it with Consul.
+//   - Apply an `egress` config entry allowing example.com:443 through the
+//     client's sidecar, bound locally to egressLocalBindPort.
+//   - Assert the sidecar's Envoy admin interface reports a dynamic listener
+//     bound to that exact address, proving the config entry actually
+//     compiled into a reachable listener rather than an address-less one.
+//   - Make a request to example.com through the egress listener's local
+//     bind port and assert it succeeds, proving traffic actually flows
+//     through the compiled listener/cluster rather than just existing.
+//   - Assert the sidecar emits an access log line recording the external
+//     host.
+func TestEgressConfigEntry(t *testing.T) {
+	if semver.IsValid(utils.TargetVersion) && semver.Compare(utils.TargetVersion, "v1.15") < 0 {
+		t.Skip()
+	}
+
+	cluster := createCluster(t)
+	client := cluster.Agents[0].GetClient()
+
+	proxyDefault := &api.ProxyConfigEntry{
+		Kind: api.ProxyDefaults,
+		Name: api.ProxyConfigGlobal,
+		AccessLogs: &api.AccessLogsConfig{
+			Enabled: true,
+		},
+	}
+	set, _, err := client.ConfigEntries().Set(proxyDefault, nil)
+	require.NoError(t, err)
+	require.True(t, set)
+
+	clientConnectProxy, err := libservice.CreateAndRegisterStaticClientSidecar(cluster.Agents[0], "", false)
+	require.NoError(t, err)
+
+	libassert.CatalogServiceExists(t, client, fmt.Sprintf("%s-sidecar-proxy", libservice.StaticClientServiceName))
+
+	egress := &api.EgressConfigEntry{
+		Kind: api.Egress,
+		Name: libservice.StaticClientServiceName,
+		Destinations: []api.EgressDestination{
+			{
+				Host:             "example.com",
+				Port:             443,
+				Protocol:         "tcp",
+				LocalBindAddress: "127.0.0.1",
+				LocalBindPort:    egressLocalBindPort,
+			},
+		},
+	}
+	set, _, err = client.ConfigEntries().Set(egress, nil)
+	require.NoError(t, err)
+	require.True(t, set)
+
+	clientSidecar, ok := clientConnectProxy.(*libservice.ConnectContainer)
+	require.True(t, ok)
+	ip, adminPort := clientSidecar.GetAdminAddr()
+
+	httpClient := cleanhttp.DefaultClient()
+	listenersURL := fmt.Sprintf("http://%s:%d/config_dump?resource=dynamic_listeners", ip, adminPort)
+	wantBoundAddr := fmt.Sprintf("\"address\":\"127.0.0.1\",\"port_value\":%d", egressLocalBindPort)
+
+	require.Eventually(t, func() bool {
+		resp, err := httpClient.Get(listenersURL)
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return false
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false
+		}
+		return strings.Contains(string(body), wantBoundAddr)
+	}, 20*time.Second, 1*time.Second, "egress listener must be bound to the configured local address/port")
+
+	_, err = httpClient.Get(listenersURL)
+	require.NoError(t, err, "error making call to Envoy admin interface")
+
+	// Drive an actual connection through the egress listener: the admin
+	// interface check above only proves the listener is bound, not that it
+	// proxies traffic. The client sends example.com as its TLS server name,
+	// which is what the listener's filter chain matches on, so a successful
+	// response here means the SNI-routed TCP proxy really forwarded the
+	// passed-through TLS session to the external host.
+	egressClient := cleanhttp.DefaultClient()
+	egressClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{ServerName: "example.com"}
+	require.Eventually(t, func() bool {
+		resp, err := egressClient.Get(fmt.Sprintf("https://localhost:%d", egressLocalBindPort))
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 20*time.Second, 1*time.Second, "a request through the egress local bind port must reach example.com")
+
+	require.Eventually(t, func() bool {
+		return libassert.ServiceLogContains(t, clientConnectProxy, "example.com")
+	}, 15*time.Second, 1*time.Second)
+}